@@ -0,0 +1,99 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// bloomFilter is a minimal Bloom filter using double hashing (Kirsch-Mitzenmacher)
+// to derive its k hash functions from two independent fnv hashes.
+type bloomFilter struct {
+	bits []uint64
+	m    uint64
+	k    uint64
+}
+
+// newBloomFilter sizes a filter for n expected items at false positive rate p.
+func newBloomFilter(n uint64, p float64) *bloomFilter {
+	if n == 0 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-1 * float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	if m == 0 {
+		m = 1
+	}
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+
+	return &bloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+func (b *bloomFilter) hashes(word string) (uint64, uint64) {
+	h1 := fnv.New64a()
+	h1.Write([]byte(word))
+	h2 := fnv.New64()
+	h2.Write([]byte(word))
+	return h1.Sum64(), h2.Sum64()
+}
+
+func (b *bloomFilter) add(word string) {
+	h1, h2 := b.hashes(word)
+	for i := uint64(0); i < b.k; i++ {
+		idx := (h1 + i*h2) % b.m
+		b.bits[idx/64] |= 1 << (idx % 64)
+	}
+}
+
+func (b *bloomFilter) contains(word string) bool {
+	h1, h2 := b.hashes(word)
+	for i := uint64(0); i < b.k; i++ {
+		idx := (h1 + i*h2) % b.m
+		if b.bits[idx/64]&(1<<(idx%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BloomSource is a ContainsSource backed by a Bloom filter, suitable for
+// corpora too large to hold in memory as a map, such as the ~800M-entry HIBP
+// Pwned Passwords download. Populate it with Add while streaming a wordlist,
+// or once per process from a precomputed dump.
+type BloomSource struct {
+	filter *bloomFilter
+}
+
+// NewBloomSource allocates a BloomSource sized for expectedItems entries at
+// the given falsePositiveRate (e.g. 0.001 for 0.1%).
+func NewBloomSource(expectedItems uint64, falsePositiveRate float64) *BloomSource {
+	return &BloomSource{filter: newBloomFilter(expectedItems, falsePositiveRate)}
+}
+
+// Add inserts word into the filter.
+func (s *BloomSource) Add(word string) {
+	s.filter.add(word)
+}
+
+// Contains implements ContainsSource.
+func (s *BloomSource) Contains(word string) bool {
+	return s.filter.contains(word)
+}
+
+// Words implements DictionarySource. BloomSource can't be enumerated, only
+// queried directly via Contains.
+func (s *BloomSource) Words() (<-chan string, error) {
+	return nil, ErrSourceNotEnumerable
+}
@@ -0,0 +1,37 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import "testing"
+
+func TestBloomSourceContains(t *testing.T) {
+	words := []string{"password", "qwerty", "letmein", "dragon", "monkey"}
+
+	source := NewBloomSource(uint64(len(words)), 0.01)
+	for _, w := range words {
+		source.Add(w)
+	}
+
+	for _, w := range words {
+		if !source.Contains(w) {
+			t.Errorf("BloomSource.Contains(%q) = false after Add, want true", w)
+		}
+	}
+	if source.Contains("definitely-not-added-to-the-filter") {
+		t.Error("BloomSource.Contains() = true for a word never added, want false")
+	}
+}
+
+func TestBloomSourceWordsNotEnumerable(t *testing.T) {
+	source := NewBloomSource(10, 0.01)
+
+	_, err := source.Words()
+	if err != ErrSourceNotEnumerable {
+		t.Errorf("BloomSource.Words() error = %v, want ErrSourceNotEnumerable", err)
+	}
+}
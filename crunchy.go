@@ -8,14 +8,12 @@
 package crunchy
 
 import (
-	"bufio"
 	"encoding/hex"
 	"hash"
-	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -24,11 +22,14 @@ import (
 
 // Validator is used to setup a new password validator with options and dictionaries
 type Validator struct {
-	options     Options
-	once        sync.Once
-	wordsMaxLen int                 // length of longest word in dictionaries
-	words       map[string]struct{} // map to index parsed dictionaries
-	hashedWords map[string]string   // maps hash-sum to password
+	options         Options
+	once            sync.Once
+	wordsMaxLen     int                 // length of longest word in dictionaries
+	words           map[string]struct{} // map to index parsed dictionaries
+	hashedWords     map[string]string   // maps hash-sum to password
+	sources         []DictionarySource  // additional registered dictionary sources
+	containsSources []ContainsSource    // sources queried directly instead of indexed
+	saltedHashes    []string            // PHC-format salted hashes found in dictionaries
 }
 
 // Options contains all the settings for a Validator
@@ -45,10 +46,51 @@ type Options struct {
 	DictionaryPath string
 	// Check haveibeenpwned.com database
 	CheckHIBP bool
+	// CollectAllErrors makes Check run every rule and return a
+	// *ValidationReport aggregating all failures, instead of returning on
+	// the first one. ValidationReport implements error, so existing callers
+	// that only check `err != nil` keep working unchanged.
+	CollectAllErrors bool
+	// HIBPCacheDir is the directory in which k-anonymity range responses from
+	// the HIBP API are cached on disk when HIBPCacheTTL is set, so repeat
+	// validations don't re-hit the API (default is crunchy-hibp-cache in
+	// os.TempDir())
+	HIBPCacheDir string
+	// HIBPCacheTTL is how long a cached HIBP range response stays valid;
+	// 0 disables caching
+	HIBPCacheTTL time.Duration
+	// CheckSaltedHashes enables scanning dictionaries for PHC-format salted
+	// hashes (bcrypt, argon2id, scrypt) using PasswordHashers
+	CheckSaltedHashes bool
+	// PasswordHashers match salted, PHC-format hashed dictionary entries;
+	// defaults to BcryptHasher, Argon2idHasher and ScryptHasher when
+	// CheckSaltedHashes is set and this is left empty
+	PasswordHashers []PasswordHasher
+	// MaxSaltedHashLen bounds the cost of CheckSaltedHashes: passwords
+	// longer than this are never run through the KDFs in PasswordHashers
+	// (>=1, default is 64). Unlike plaintext dictionary entries, salted
+	// hashes don't reveal the length of the password they were derived
+	// from, so this can't be bounded by wordsMaxLen the way the
+	// mangled-dictionary lookup is
+	MaxSaltedHashLen int
+	// UnicodeFold normalizes dictionary words and passwords through NFKC,
+	// strips combining marks, and maps confusable runes (Cyrillic/Greek
+	// look-alikes, full-width forms, mathematical alphanumerics) onto their
+	// ASCII skeleton before dictionary lookups, catching e.g. "pässwörd" or
+	// "раssword" (Cyrillic)
+	UnicodeFold bool
 	// MustContainDigit requires at least one digit for a valid password
 	MustContainDigit bool
 	// MustContainSymbol requires at least one special symbol for a valid password
 	MustContainSymbol bool
+	// RatingMode selects the algorithm used by Rate (default is Heuristic)
+	RatingMode RatingMode
+	// MinEntropyBits is the minimum Shannon entropy (in bits) required for a valid
+	// password, regardless of length (0 disables the check)
+	MinEntropyBits float64
+	// GenerateMaxAttempts is how many candidates Generate tries before giving
+	// up (>=1, default is 50)
+	GenerateMaxAttempts int
 }
 
 // NewValidator returns a new password validator with default settings
@@ -73,6 +115,15 @@ func NewValidatorWithOpts(options Options) *Validator {
 	if options.MinDist < 0 {
 		options.MinDist = 3
 	}
+	if options.GenerateMaxAttempts <= 0 {
+		options.GenerateMaxAttempts = 50
+	}
+	if options.CheckSaltedHashes && options.PasswordHashers == nil {
+		options.PasswordHashers = []PasswordHasher{BcryptHasher, Argon2idHasher, ScryptHasher}
+	}
+	if options.MaxSaltedHashLen <= 0 {
+		options.MaxSaltedHashLen = 64
+	}
 
 	return &Validator{
 		options:     options,
@@ -81,26 +132,31 @@ func NewValidatorWithOpts(options Options) *Validator {
 	}
 }
 
-// indexDictionaries parses dictionaries/wordlists
+// indexDictionaries parses dictionaries/wordlists from every registered
+// DictionarySource, plus the legacy DictionaryPath glob if set
 func (v *Validator) indexDictionaries() {
-	if v.options.DictionaryPath == "" {
-		return
-	}
-
-	dicts, err := filepath.Glob(filepath.Join(v.options.DictionaryPath, "*"))
-	if err != nil {
-		return
+	sources := v.sources
+	if v.options.DictionaryPath != "" {
+		sources = append([]DictionarySource{FileSystemSource{Path: v.options.DictionaryPath}}, sources...)
 	}
 
-	for _, dict := range dicts {
-		file, err := os.Open(dict)
+	for _, source := range sources {
+		words, err := source.Words()
+		if err == ErrSourceNotEnumerable {
+			// queried directly via ContainsSource instead, see foundInDictionaries
+			continue
+		}
 		if err != nil {
 			continue
 		}
 
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			nw := normalize(scanner.Text())
+		for w := range words {
+			if v.options.CheckSaltedHashes && isSaltedHash(w, v.options.PasswordHashers) {
+				v.saltedHashes = append(v.saltedHashes, w)
+				continue
+			}
+
+			nw := normalize(w, v.options.UnicodeFold)
 			nwlen := len(nw)
 			if nwlen > v.wordsMaxLen {
 				v.wordsMaxLen = nwlen
@@ -116,8 +172,6 @@ func (v *Validator) indexDictionaries() {
 				v.hashedWords[hashsum(nw, hasher)] = nw
 			}
 		}
-
-		file.Close()
 	}
 }
 
@@ -126,12 +180,27 @@ func (v *Validator) IndexDictionaries() {
 	v.once.Do(v.indexDictionaries)
 }
 
+// AddDictionarySource registers an additional dictionary source to be
+// consulted alongside DictionaryPath. Sources that implement Words are
+// indexed into memory like a regular wordlist; sources that implement
+// ContainsSource instead (such as a bloom-filter-backed corpus too large to
+// hold in memory) are queried directly on each check.
+//
+// Must be called before the first call to Check, Rate or IndexDictionaries,
+// since indexing only happens once.
+func (v *Validator) AddDictionarySource(source DictionarySource) {
+	v.sources = append(v.sources, source)
+	if cs, ok := source.(ContainsSource); ok {
+		v.containsSources = append(v.containsSources, cs)
+	}
+}
+
 // foundInDictionaries returns whether a (mangled) string exists in the indexed dictionaries
 func (v *Validator) foundInDictionaries(s string) error {
 	v.IndexDictionaries()
 
-	pw := normalize(s)   // normalized password
-	revpw := reverse(pw) // reversed password
+	pw := normalize(s, v.options.UnicodeFold) // normalized password
+	revpw := reverse(pw)                      // reversed password
 	pwlen := len(pw)
 
 	// let's check perfect matches first
@@ -145,6 +214,14 @@ func (v *Validator) foundInDictionaries(s string) error {
 		}
 	}
 
+	// query ContainsSource-backed sources directly, e.g. a bloom filter
+	// covering a corpus too large to index in memory
+	for _, cs := range v.containsSources {
+		if cs.Contains(pw) {
+			return &DictionaryError{ErrDictionary, pw, 0}
+		}
+	}
+
 	// find hashed dictionary entries
 	if pwindex, err := hex.DecodeString(pw); err == nil {
 		if word, ok := v.hashedWords[string(pwindex)]; ok {
@@ -152,6 +229,22 @@ func (v *Validator) foundInDictionaries(s string) error {
 		}
 	}
 
+	// find salted, PHC-format hashed dictionary entries (bcrypt/argon2id/scrypt);
+	// bounded by MaxSaltedHashLen since each entry requires running the KDF,
+	// and salted hashes (unlike plaintext words) don't reveal wordsMaxLen.
+	// Unlike the plaintext/fixed-hash paths above, the *unnormalized* input is
+	// used: these hashes are of the exact, case-sensitive original password,
+	// and can't be normalized the way indexed dictionary words are
+	if v.options.CheckSaltedHashes && pwlen <= v.options.MaxSaltedHashLen {
+		for _, encoded := range v.saltedHashes {
+			for _, hasher := range v.options.PasswordHashers {
+				if strings.HasPrefix(encoded, hasher.Prefix()) && hasher.Match(s, encoded) {
+					return &HashedDictionaryError{ErrSaltedHashedDictionary, encoded}
+				}
+			}
+		}
+	}
+
 	// find mangled / reversed passwords
 	// we can skip this if the pw is longer than the longest word plus our minimum distance
 	if pwlen <= v.wordsMaxLen+v.options.MinDist {
@@ -168,9 +261,24 @@ func (v *Validator) foundInDictionaries(s string) error {
 	return nil
 }
 
-// Check validates a password for common flaws
-// It returns nil if the password is considered acceptable.
+// Check validates a password for common flaws.
+// It returns nil if the password is considered acceptable. With
+// Options.CollectAllErrors set, the returned error is a *ValidationReport
+// aggregating every failed rule instead of just the first one.
 func (v *Validator) Check(password string) error {
+	if v.options.CollectAllErrors {
+		report := v.checkAll(password)
+		if len(report.Issues) == 0 {
+			return nil
+		}
+		return report
+	}
+	return v.checkFirstFail(password)
+}
+
+// checkFirstFail validates a password for common flaws, returning on the
+// first rule it fails.
+func (v *Validator) checkFirstFail(password string) error {
 	if strings.TrimSpace(password) == "" {
 		return ErrEmpty
 	}
@@ -201,13 +309,17 @@ func (v *Validator) Check(password string) error {
 		return ErrTooSystematic
 	}
 
+	if v.options.MinEntropyBits > 0 && entropyBits(password) < v.options.MinEntropyBits {
+		return ErrLowEntropy
+	}
+
 	err := v.foundInDictionaries(password)
 	if err != nil {
 		return err
 	}
 
 	if v.options.CheckHIBP {
-		err := foundInHIBP(password)
+		err := v.foundInHIBP(password)
 		if err != nil {
 			return err
 		}
@@ -222,6 +334,15 @@ func (v *Validator) Rate(password string) (uint, error) {
 		return 0, err
 	}
 
+	if v.options.RatingMode == Entropy {
+		return rateEntropy(password), nil
+	}
+	return rateHeuristic(password), nil
+}
+
+// rateHeuristic grades a password's strength from 0 (weak) to 100 (strong)
+// using a cracklib-inspired heuristic.
+func rateHeuristic(password string) uint {
 	l := len(password)
 	systematics := countSystematicChars(password)
 	repeats := l - countUniqueChars(password)
@@ -277,5 +398,5 @@ func (v *Validator) Rate(password string) (uint, error) {
 	} else if n > 100 {
 		n = 100
 	}
-	return uint(n), nil
+	return uint(n)
 }
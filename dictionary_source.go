@@ -0,0 +1,129 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// ErrSourceNotEnumerable is returned by DictionarySource.Words when the
+// source cannot be streamed into memory and must instead be queried through
+// ContainsSource.
+var ErrSourceNotEnumerable = errors.New("dictionary source cannot be enumerated")
+
+// DictionarySource supplies words to be indexed by a Validator.
+type DictionarySource interface {
+	// Words streams every entry in the source. Implementations that are too
+	// large to enumerate (e.g. a bloom filter backing the ~800M-entry HIBP
+	// Pwned Passwords corpus) should return ErrSourceNotEnumerable and
+	// implement ContainsSource instead.
+	Words() (<-chan string, error)
+}
+
+// ContainsSource is implemented by dictionary sources that can answer
+// membership queries directly, without being indexed into memory.
+type ContainsSource interface {
+	Contains(word string) bool
+}
+
+// streamFile scans a single file line-by-line and sends every line to ch.
+func streamFile(path string, ch chan<- string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		ch <- scanner.Text()
+	}
+}
+
+// FileSystemSource indexes every file found in Path, mirroring crunchy's
+// original /usr/share/dict glob.
+type FileSystemSource struct {
+	Path string
+}
+
+// Words implements DictionarySource.
+func (s FileSystemSource) Words() (<-chan string, error) {
+	dicts, err := filepath.Glob(filepath.Join(s.Path, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for _, dict := range dicts {
+			streamFile(dict, ch)
+		}
+	}()
+	return ch, nil
+}
+
+// GzipSource indexes every gzip-compressed wordlist file found in Path, e.g.
+// a downloaded rockyou.txt.gz.
+type GzipSource struct {
+	Path string
+}
+
+// Words implements DictionarySource.
+func (s GzipSource) Words() (<-chan string, error) {
+	dicts, err := filepath.Glob(filepath.Join(s.Path, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for _, dict := range dicts {
+			file, err := os.Open(dict)
+			if err != nil {
+				continue
+			}
+
+			gzr, err := gzip.NewReader(file)
+			if err != nil {
+				file.Close()
+				continue
+			}
+
+			scanner := bufio.NewScanner(gzr)
+			for scanner.Scan() {
+				ch <- scanner.Text()
+			}
+
+			gzr.Close()
+			file.Close()
+		}
+	}()
+	return ch, nil
+}
+
+// MemorySource indexes an in-memory list of words, useful for tests or
+// small, custom blocklists assembled at runtime.
+type MemorySource struct {
+	List []string
+}
+
+// Words implements DictionarySource.
+func (s MemorySource) Words() (<-chan string, error) {
+	ch := make(chan string, len(s.List))
+	for _, w := range s.List {
+		ch <- w
+	}
+	close(ch)
+	return ch, nil
+}
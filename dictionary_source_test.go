@@ -0,0 +1,48 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import "testing"
+
+func TestMemorySourceWords(t *testing.T) {
+	source := MemorySource{List: []string{"alpha", "bravo", "charlie"}}
+
+	words, err := source.Words()
+	if err != nil {
+		t.Fatalf("MemorySource.Words() error = %v", err)
+	}
+
+	var got []string
+	for w := range words {
+		got = append(got, w)
+	}
+
+	if len(got) != len(source.List) {
+		t.Fatalf("MemorySource.Words() yielded %d words, want %d", len(got), len(source.List))
+	}
+	for i, w := range source.List {
+		if got[i] != w {
+			t.Errorf("MemorySource.Words()[%d] = %q, want %q", i, got[i], w)
+		}
+	}
+}
+
+func TestAddDictionarySourceRegistersContainsSource(t *testing.T) {
+	v := NewValidatorWithOpts(Options{DictionaryPath: ""})
+
+	bloom := NewBloomSource(4, 0.01)
+	bloom.Add("hunter2")
+	v.AddDictionarySource(bloom)
+
+	if len(v.containsSources) != 1 {
+		t.Fatalf("expected 1 registered ContainsSource, got %d", len(v.containsSources))
+	}
+	if !v.containsSources[0].Contains("hunter2") {
+		t.Error("registered ContainsSource does not contain the word that was Add()ed")
+	}
+}
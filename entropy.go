@@ -0,0 +1,123 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import (
+	"errors"
+	"math"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrLowEntropy is returned when a password's Shannon entropy is below
+// Options.MinEntropyBits.
+var ErrLowEntropy = errors.New("password entropy is too low")
+
+// RatingMode selects the algorithm Rate uses to grade a password's strength.
+type RatingMode int
+
+const (
+	// Heuristic rates a password using crunchy's cracklib-inspired heuristic (default).
+	Heuristic RatingMode = iota
+	// Entropy rates a password by its Shannon entropy / character-pool bit-strength,
+	// as popularized by go-password-validator.
+	Entropy
+)
+
+// targetBits is the entropy considered "full strength" (100) when mapping
+// bits onto the 0..100 scale used by Rate.
+const targetBits = 80.0
+
+const (
+	poolLower    = 26
+	poolUpper    = 26
+	poolDigit    = 10
+	poolSymbol   = 32
+	poolNonASCII = 64 // bonus pool size granted when non-ASCII runes are present
+)
+
+// entropyBits estimates the Shannon entropy of password in bits: the size of
+// the character pool the password draws from, raised to the power of its
+// length, then log2'd. For each rune repeated c>1 times, (c-1)*log2(c) bits
+// are subtracted - i.e. every repeat occurrence is penalized, not just the
+// first - so that strings like "aaaaaaaaaa" don't score well despite their
+// length. This is deliberately stricter than penalizing each repeated rune
+// once (subtracting a flat log2(c) per distinct repeated rune): that weaker
+// form lets long runs of a single character still clear a moderate
+// MinEntropyBits threshold.
+func entropyBits(password string) float64 {
+	pool := 0
+	counts := make(map[rune]int)
+
+	var hasLower, hasUpper, hasDigit, hasSymbol, hasNonASCII bool
+	for _, r := range password {
+		counts[r]++
+
+		switch {
+		case r > unicode.MaxASCII:
+			hasNonASCII = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	if hasLower {
+		pool += poolLower
+	}
+	if hasUpper {
+		pool += poolUpper
+	}
+	if hasDigit {
+		pool += poolDigit
+	}
+	if hasSymbol {
+		pool += poolSymbol
+	}
+	if hasNonASCII {
+		pool += poolNonASCII
+	}
+	if pool == 0 {
+		return 0
+	}
+
+	bits := float64(utf8.RuneCountInString(password)) * math.Log2(float64(pool))
+	for _, c := range counts {
+		if c > 1 {
+			// penalize every repeat occurrence, not just the first, so e.g.
+			// "aaaaaaaaaa" doesn't still score as mid-strength
+			bits -= float64(c-1) * math.Log2(float64(c))
+		}
+	}
+	if bits < 0 {
+		bits = 0
+	}
+
+	return bits
+}
+
+// rateEntropy grades a password's strength from 0 (weak) to 100 (strong) by
+// mapping its Shannon entropy onto the 0..100 scale, treating targetBits as
+// full strength.
+func rateEntropy(password string) uint {
+	bits := entropyBits(password)
+
+	n := bits * 100 / targetBits
+	if n > 100 {
+		n = 100
+	} else if n < 0 {
+		n = 0
+	}
+
+	return uint(n)
+}
@@ -0,0 +1,42 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import "testing"
+
+func TestEntropyBitsPenalizesRepeats(t *testing.T) {
+	repeated := entropyBits("aaaaaaaaaa")
+	diverse := entropyBits("aB3!kZ9#mQ")
+
+	if repeated >= diverse {
+		t.Fatalf("expected repeated-rune password to score lower entropy than a diverse one, got %.2f >= %.2f", repeated, diverse)
+	}
+	if repeated > 25 {
+		t.Fatalf("expected \"aaaaaaaaaa\" to score well under 25 bits, got %.2f", repeated)
+	}
+}
+
+func TestEntropyBitsPoolGrowsWithClasses(t *testing.T) {
+	lower := entropyBits("abcdefgh")
+	mixed := entropyBits("aBcDeFg1")
+
+	if mixed <= lower {
+		t.Fatalf("expected a mixed-class password to score higher entropy than lowercase-only, got %.2f <= %.2f", mixed, lower)
+	}
+}
+
+func TestRateEntropyClampedTo100(t *testing.T) {
+	if got := rateEntropy(""); got != 0 {
+		t.Errorf("rateEntropy(\"\") = %d, want 0", got)
+	}
+
+	long := "xQ7#kP2$mZ9@vR4!tY6&wS1*uB3^jN8~"
+	if got := rateEntropy(long); got > 100 {
+		t.Errorf("rateEntropy(%q) = %d, want <= 100", long, got)
+	}
+}
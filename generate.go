@@ -0,0 +1,121 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// ErrGenerateFailed is returned by Generate when no candidate satisfying the
+// Validator's policy could be produced within Options.GenerateMaxAttempts.
+var ErrGenerateFailed = errors.New("could not generate a password satisfying the policy")
+
+const (
+	lowerPool  = "abcdefghijklmnopqrstuvwxyz"
+	upperPool  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitPool  = "0123456789"
+	symbolPool = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+// Generate produces a crypto/rand-sourced password of the given length that
+// is guaranteed to pass this Validator's Check, retrying up to
+// Options.GenerateMaxAttempts times if a candidate collides with a
+// dictionary or HIBP entry.
+func (v *Validator) Generate(length int) (string, error) {
+	if length < v.options.MinLength {
+		length = v.options.MinLength
+	}
+
+	for i := 0; i < v.options.GenerateMaxAttempts; i++ {
+		candidate, err := v.generateCandidate(length)
+		if err != nil {
+			return "", err
+		}
+		if v.Check(candidate) == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", ErrGenerateFailed
+}
+
+// MustGenerate is like Generate, but panics if a password can't be produced.
+func (v *Validator) MustGenerate(length int) string {
+	password, err := v.Generate(length)
+	if err != nil {
+		panic(err)
+	}
+	return password
+}
+
+// generateCandidate draws a single password candidate of the given length:
+// one lowercase and one uppercase rune, plus one digit and/or symbol where
+// the policy requires them, then uniformly from all four pools, then
+// Fisher-Yates shuffled.
+func (v *Validator) generateCandidate(length int) (string, error) {
+	pools := []string{lowerPool, upperPool, digitPool, symbolPool}
+
+	required := []string{lowerPool, upperPool}
+	if v.options.MustContainDigit {
+		required = append(required, digitPool)
+	}
+	if v.options.MustContainSymbol {
+		required = append(required, symbolPool)
+	}
+
+	runes := make([]rune, 0, length)
+	for _, pool := range required {
+		r, err := randomRune(pool)
+		if err != nil {
+			return "", err
+		}
+		runes = append(runes, r)
+	}
+
+	for len(runes) < length {
+		poolIdx, err := randIntn(len(pools))
+		if err != nil {
+			return "", err
+		}
+		r, err := randomRune(pools[poolIdx])
+		if err != nil {
+			return "", err
+		}
+		runes = append(runes, r)
+	}
+
+	for i := len(runes) - 1; i > 0; i-- {
+		j, err := randIntn(i + 1)
+		if err != nil {
+			return "", err
+		}
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes), nil
+}
+
+// randIntn returns a crypto/rand-sourced integer in [0, n).
+func randIntn(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
+
+// randomRune picks a crypto/rand-sourced rune from pool.
+func randomRune(pool string) (rune, error) {
+	i, err := randIntn(len(pool))
+	if err != nil {
+		return 0, err
+	}
+	return rune(pool[i]), nil
+}
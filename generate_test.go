@@ -0,0 +1,62 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import "testing"
+
+func TestGenerateSatisfiesPolicy(t *testing.T) {
+	v := NewValidatorWithOpts(Options{
+		MinLength:         12,
+		MinDiff:           5,
+		MustContainDigit:  true,
+		MustContainSymbol: true,
+		DictionaryPath:    "",
+	})
+
+	for i := 0; i < 20; i++ {
+		password, err := v.Generate(12)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if len(password) != 12 {
+			t.Fatalf("Generate(12) produced password of length %d: %q", len(password), password)
+		}
+		if err := v.Check(password); err != nil {
+			t.Fatalf("Generate produced a password that fails Check: %q: %v", password, err)
+		}
+	}
+}
+
+func TestGenerateGrowsToMinLength(t *testing.T) {
+	v := NewValidatorWithOpts(Options{MinLength: 16, DictionaryPath: ""})
+
+	password, err := v.Generate(4)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(password) != 16 {
+		t.Fatalf("Generate(4) with MinLength 16 produced length %d, want 16", len(password))
+	}
+}
+
+func TestMustGeneratePanicsOnFailure(t *testing.T) {
+	v := NewValidatorWithOpts(Options{
+		MinLength:           8,
+		GenerateMaxAttempts: 1,
+		DictionaryPath:      "",
+	})
+	// Force every candidate to fail Check by requiring an impossibly large pool.
+	v.options.MinDiff = 1000
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected MustGenerate to panic when no candidate satisfies the policy")
+		}
+	}()
+	v.MustGenerate(8)
+}
@@ -0,0 +1,122 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrHIBP is returned when a password is found in the Have I Been Pwned
+// Pwned Passwords database.
+var ErrHIBP = errors.New("password was exposed in a known data breach")
+
+// hibpRangeURL is the k-anonymity range endpoint of the HIBP API; only the
+// first 5 characters of the password's SHA-1 sum are ever sent.
+const hibpRangeURL = "https://api.pwnedpasswords.com/range/"
+
+// foundInHIBP checks password against the HIBP Pwned Passwords database
+// using the k-anonymity range API, optionally caching range responses on
+// disk per Options.HIBPCacheDir / Options.HIBPCacheTTL.
+func (v *Validator) foundInHIBP(password string) error {
+	sum := sha1.Sum([]byte(password))
+	hexsum := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hexsum[:5], hexsum[5:]
+
+	body, err := v.hibpRange(prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) == 2 && parts[0] == suffix {
+			return ErrHIBP
+		}
+	}
+
+	return nil
+}
+
+// hibpRange fetches the set of hash suffixes for prefix, serving a cached
+// response from disk if HIBPCacheTTL is set and the cache entry hasn't
+// expired.
+func (v *Validator) hibpRange(prefix string) ([]byte, error) {
+	if v.options.HIBPCacheTTL > 0 {
+		if body, ok := v.hibpCacheGet(prefix); ok {
+			return body, nil
+		}
+	}
+
+	resp, err := http.Get(hibpRangeURL + prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("hibp range request for prefix %q failed: %s", prefix, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.options.HIBPCacheTTL > 0 {
+		v.hibpCachePut(prefix, body)
+	}
+
+	return body, nil
+}
+
+func (v *Validator) hibpCacheDir() string {
+	if v.options.HIBPCacheDir != "" {
+		return v.options.HIBPCacheDir
+	}
+	return filepath.Join(os.TempDir(), "crunchy-hibp-cache")
+}
+
+func (v *Validator) hibpCachePath(prefix string) string {
+	return filepath.Join(v.hibpCacheDir(), prefix+".txt")
+}
+
+func (v *Validator) hibpCacheGet(prefix string) ([]byte, bool) {
+	path := v.hibpCachePath(prefix)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if time.Since(info.ModTime()) > v.options.HIBPCacheTTL {
+		return nil, false
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return body, true
+}
+
+func (v *Validator) hibpCachePut(prefix string, body []byte) {
+	dir := v.hibpCacheDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(v.hibpCachePath(prefix), body, 0o600)
+}
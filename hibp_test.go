@@ -0,0 +1,49 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHIBPCacheRoundTrip(t *testing.T) {
+	v := NewValidatorWithOpts(Options{
+		DictionaryPath: "",
+		HIBPCacheDir:   t.TempDir(),
+		HIBPCacheTTL:   time.Minute,
+	})
+
+	if _, ok := v.hibpCacheGet("ABCDE"); ok {
+		t.Fatal("hibpCacheGet() found an entry before any Put")
+	}
+
+	v.hibpCachePut("ABCDE", []byte("some-response-body"))
+
+	body, ok := v.hibpCacheGet("ABCDE")
+	if !ok {
+		t.Fatal("hibpCacheGet() found nothing after Put")
+	}
+	if string(body) != "some-response-body" {
+		t.Errorf("hibpCacheGet() = %q, want %q", body, "some-response-body")
+	}
+}
+
+func TestHIBPCacheExpiresAfterTTL(t *testing.T) {
+	v := NewValidatorWithOpts(Options{
+		DictionaryPath: "",
+		HIBPCacheDir:   t.TempDir(),
+		HIBPCacheTTL:   -time.Second, // already expired
+	})
+
+	v.hibpCachePut("ABCDE", []byte("stale"))
+
+	if _, ok := v.hibpCacheGet("ABCDE"); ok {
+		t.Error("hibpCacheGet() returned an entry older than HIBPCacheTTL")
+	}
+}
@@ -0,0 +1,156 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ErrorCode is a stable, machine-readable identifier for a failed rule, so
+// frontends can key off it instead of parsing error messages.
+type ErrorCode string
+
+// Error codes returned in a ValidationReport's Issues.
+const (
+	CodeEmpty                  ErrorCode = "empty"
+	CodeTooShort               ErrorCode = "too_short"
+	CodeTooFewChars            ErrorCode = "too_few_chars"
+	CodeNoDigit                ErrorCode = "no_digit"
+	CodeNoSymbol               ErrorCode = "no_symbol"
+	CodeTooSystematic          ErrorCode = "too_systematic"
+	CodeLowEntropy             ErrorCode = "low_entropy"
+	CodeDictionary             ErrorCode = "dictionary"
+	CodeMangledDictionary      ErrorCode = "mangled_dictionary"
+	CodeHashedDictionary       ErrorCode = "hashed_dictionary"
+	CodeSaltedHashedDictionary ErrorCode = "salted_hashed_dictionary"
+	CodeHIBP                   ErrorCode = "hibp"
+)
+
+// ValidationIssue describes a single failed rule.
+type ValidationIssue struct {
+	// Code is a stable, machine-readable identifier for the failed rule.
+	Code ErrorCode
+	// Message is a human-readable description of the failure.
+	Message string
+	// Err is the underlying sentinel/detail error for this rule, e.g.
+	// ErrTooShort or a *DictionaryError.
+	Err error
+	// Word is the matched dictionary word (for salted hashed dictionaries,
+	// the matched encoded hash), if this issue came from a dictionary
+	// lookup. It is left empty for HIBP issues.
+	Word string
+	// Distance is the WagnerFischer edit distance of the match, for mangled
+	// dictionary hits.
+	Distance int
+	// Bits is the password's estimated Shannon entropy, for low-entropy hits.
+	Bits float64
+}
+
+// Error implements error.
+func (i *ValidationIssue) Error() string {
+	return i.Message
+}
+
+// ValidationReport aggregates every rule a password failed, for callers that
+// want to show a user all the fixes they need in one round-trip. It
+// implements error, and Unwrap returns the first issue's underlying error,
+// so existing `err != nil` / errors.Is callers keep working unchanged.
+type ValidationReport struct {
+	Issues []*ValidationIssue
+}
+
+// Error implements error, rendering the first issue's message.
+func (r *ValidationReport) Error() string {
+	if len(r.Issues) == 0 {
+		return ""
+	}
+	return r.Issues[0].Error()
+}
+
+// Unwrap returns the first issue's underlying error.
+func (r *ValidationReport) Unwrap() error {
+	if len(r.Issues) == 0 {
+		return nil
+	}
+	return r.Issues[0].Err
+}
+
+var (
+	digitRegexp  = regexp.MustCompile(`[0-9]+`)
+	symbolRegexp = regexp.MustCompile(`[^\w\s]+`)
+)
+
+// checkAll runs every rule against password and collects every failure into
+// a ValidationReport, rather than returning on the first one.
+func (v *Validator) checkAll(password string) *ValidationReport {
+	report := &ValidationReport{}
+
+	if strings.TrimSpace(password) == "" {
+		report.Issues = append(report.Issues, &ValidationIssue{Code: CodeEmpty, Err: ErrEmpty, Message: ErrEmpty.Error()})
+		return report
+	}
+
+	if len(password) < v.options.MinLength {
+		report.Issues = append(report.Issues, &ValidationIssue{Code: CodeTooShort, Err: ErrTooShort, Message: ErrTooShort.Error()})
+	}
+	if countUniqueChars(password) < v.options.MinDiff {
+		report.Issues = append(report.Issues, &ValidationIssue{Code: CodeTooFewChars, Err: ErrTooFewChars, Message: ErrTooFewChars.Error()})
+	}
+	if v.options.MustContainDigit && !digitRegexp.MatchString(password) {
+		report.Issues = append(report.Issues, &ValidationIssue{Code: CodeNoDigit, Err: ErrNoDigits, Message: ErrNoDigits.Error()})
+	}
+	if v.options.MustContainSymbol && !symbolRegexp.MatchString(password) {
+		report.Issues = append(report.Issues, &ValidationIssue{Code: CodeNoSymbol, Err: ErrNoSymbols, Message: ErrNoSymbols.Error()})
+	}
+
+	// Inspired by cracklib
+	maxrepeat := 3.0 + (0.09 * float64(len(password)))
+	if countSystematicChars(password) > int(maxrepeat) {
+		report.Issues = append(report.Issues, &ValidationIssue{Code: CodeTooSystematic, Err: ErrTooSystematic, Message: ErrTooSystematic.Error()})
+	}
+
+	if v.options.MinEntropyBits > 0 {
+		if bits := entropyBits(password); bits < v.options.MinEntropyBits {
+			report.Issues = append(report.Issues, &ValidationIssue{Code: CodeLowEntropy, Err: ErrLowEntropy, Message: ErrLowEntropy.Error(), Bits: bits})
+		}
+	}
+
+	if err := v.foundInDictionaries(password); err != nil {
+		report.Issues = append(report.Issues, dictionaryIssue(err))
+	}
+
+	if v.options.CheckHIBP {
+		if err := v.foundInHIBP(password); err != nil {
+			report.Issues = append(report.Issues, &ValidationIssue{Code: CodeHIBP, Err: err, Message: err.Error()})
+		}
+	}
+
+	return report
+}
+
+// dictionaryIssue maps the error returned by foundInDictionaries onto a
+// ValidationIssue, picking out the matched word/distance where available.
+func dictionaryIssue(err error) *ValidationIssue {
+	switch e := err.(type) {
+	case *DictionaryError:
+		code := CodeDictionary
+		if e.Err == ErrMangledDictionary {
+			code = CodeMangledDictionary
+		}
+		return &ValidationIssue{Code: code, Err: err, Message: err.Error(), Word: e.Word, Distance: e.Distance}
+	case *HashedDictionaryError:
+		code := CodeHashedDictionary
+		if e.Err == ErrSaltedHashedDictionary {
+			code = CodeSaltedHashedDictionary
+		}
+		return &ValidationIssue{Code: code, Err: err, Message: err.Error(), Word: e.Word}
+	default:
+		return &ValidationIssue{Code: CodeDictionary, Err: err, Message: err.Error()}
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import "testing"
+
+func TestCheckCollectsAllErrors(t *testing.T) {
+	v := NewValidatorWithOpts(Options{
+		MinLength:         10,
+		MinDiff:           5,
+		MustContainDigit:  true,
+		MustContainSymbol: true,
+		CollectAllErrors:  true,
+		DictionaryPath:    "",
+	})
+
+	err := v.Check("aa")
+	if err == nil {
+		t.Fatal("Check(\"aa\") = nil, want an error")
+	}
+
+	report, ok := err.(*ValidationReport)
+	if !ok {
+		t.Fatalf("Check() returned %T, want *ValidationReport", err)
+	}
+
+	wantCodes := map[ErrorCode]bool{
+		CodeTooShort:    true,
+		CodeTooFewChars: true,
+		CodeNoDigit:     true,
+		CodeNoSymbol:    true,
+	}
+	gotCodes := map[ErrorCode]bool{}
+	for _, issue := range report.Issues {
+		gotCodes[issue.Code] = true
+	}
+	for code := range wantCodes {
+		if !gotCodes[code] {
+			t.Errorf("ValidationReport missing expected issue code %q; got %v", code, gotCodes)
+		}
+	}
+
+	if len(report.Issues) < len(wantCodes) {
+		t.Errorf("expected at least %d issues, got %d", len(wantCodes), len(report.Issues))
+	}
+}
+
+func TestCheckWithoutCollectAllErrorsReturnsFirstFailure(t *testing.T) {
+	v := NewValidatorWithOpts(Options{
+		MinLength:      10,
+		MinDiff:        5,
+		DictionaryPath: "",
+	})
+
+	err := v.Check("aa")
+	if _, ok := err.(*ValidationReport); ok {
+		t.Fatal("Check() returned a *ValidationReport with CollectAllErrors unset")
+	}
+	if err != ErrTooShort {
+		t.Errorf("Check(\"aa\") = %v, want ErrTooShort", err)
+	}
+}
+
+func TestValidationReportUnwrapsToFirstIssue(t *testing.T) {
+	report := &ValidationReport{Issues: []*ValidationIssue{
+		{Code: CodeTooShort, Err: ErrTooShort, Message: ErrTooShort.Error()},
+		{Code: CodeNoDigit, Err: ErrNoDigits, Message: ErrNoDigits.Error()},
+	}}
+
+	if got := report.Unwrap(); got != ErrTooShort {
+		t.Errorf("ValidationReport.Unwrap() = %v, want ErrTooShort", got)
+	}
+	if got := report.Error(); got != ErrTooShort.Error() {
+		t.Errorf("ValidationReport.Error() = %q, want %q", got, ErrTooShort.Error())
+	}
+}
+
+func TestEmptyReportHasNoIssues(t *testing.T) {
+	report := &ValidationReport{}
+	if report.Unwrap() != nil {
+		t.Error("empty ValidationReport.Unwrap() should be nil")
+	}
+	if report.Error() != "" {
+		t.Error("empty ValidationReport.Error() should be empty")
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrSaltedHashedDictionary is returned when a password matches a salted,
+// PHC-format hashed dictionary entry (bcrypt, argon2id or scrypt).
+var ErrSaltedHashedDictionary = errors.New("password matches a salted hashed dictionary entry")
+
+// PasswordHasher matches a password against a salted, PHC-format encoded
+// hash found in a dictionary, e.g. "$2a$...", "$argon2id$..." or "$scrypt$...".
+type PasswordHasher interface {
+	// Match reports whether password hashes to encoded.
+	Match(password string, encoded string) bool
+	// Prefix is the PHC-format prefix this hasher recognizes.
+	Prefix() string
+}
+
+// isSaltedHash reports whether line looks like a PHC-format hash recognized
+// by one of hashers.
+func isSaltedHash(line string, hashers []PasswordHasher) bool {
+	for _, hasher := range hashers {
+		if strings.HasPrefix(line, hasher.Prefix()) {
+			return true
+		}
+	}
+	return false
+}
+
+// BcryptHasher matches bcrypt hashes ("$2a$", "$2b$", "$2y$").
+var BcryptHasher PasswordHasher = bcryptHasher{}
+
+type bcryptHasher struct{}
+
+func (bcryptHasher) Prefix() string { return "$2" }
+
+func (bcryptHasher) Match(password string, encoded string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) == nil
+}
+
+// Argon2idHasher matches argon2id PHC hashes, e.g.
+// "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>".
+var Argon2idHasher PasswordHasher = argon2idHasher{}
+
+type argon2idHasher struct{}
+
+func (argon2idHasher) Prefix() string { return "$argon2id$" }
+
+func (argon2idHasher) Match(password string, encoded string) bool {
+	parts := strings.Split(strings.TrimPrefix(encoded, "$"), "$")
+	// parts: argon2id, v=19, m=...,t=...,p=..., salt, hash
+	if len(parts) != 5 {
+		return false
+	}
+
+	var memory, time uint64
+	var threads uint64
+	for _, kv := range strings.Split(parts[2], ",") {
+		pair := strings.SplitN(kv, "=", 2)
+		if len(pair) != 2 {
+			return false
+		}
+		val, err := strconv.ParseUint(pair[1], 10, 32)
+		if err != nil {
+			return false
+		}
+		switch pair[0] {
+		case "m":
+			memory = val
+		case "t":
+			time = val
+		case "p":
+			threads = val
+		}
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+
+	computed := argon2.IDKey([]byte(password), salt, uint32(time), uint32(memory), uint8(threads), uint32(len(hash)))
+	return constantTimeEqual(computed, hash)
+}
+
+// ScryptHasher matches scrypt PHC hashes, e.g. "$scrypt$ln=15,r=8,p=1$<salt>$<hash>".
+var ScryptHasher PasswordHasher = scryptHasher{}
+
+type scryptHasher struct{}
+
+func (scryptHasher) Prefix() string { return "$scrypt$" }
+
+func (scryptHasher) Match(password string, encoded string) bool {
+	parts := strings.Split(strings.TrimPrefix(encoded, "$"), "$")
+	// parts: scrypt, ln=...,r=...,p=..., salt, hash
+	if len(parts) != 4 {
+		return false
+	}
+
+	var logN, r, p uint64
+	for _, kv := range strings.Split(parts[1], ",") {
+		pair := strings.SplitN(kv, "=", 2)
+		if len(pair) != 2 {
+			return false
+		}
+		val, err := strconv.ParseUint(pair[1], 10, 32)
+		if err != nil {
+			return false
+		}
+		switch pair[0] {
+		case "ln":
+			logN = val
+		case "r":
+			r = val
+		case "p":
+			p = val
+		}
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+
+	computed, err := scrypt.Key([]byte(password), salt, 1<<logN, int(r), int(p), len(hash))
+	if err != nil {
+		return false
+	}
+	return constantTimeEqual(computed, hash)
+}
+
+func constantTimeEqual(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	var v byte
+	for i := range a {
+		v |= a[i] ^ b[i]
+	}
+	return v == 0
+}
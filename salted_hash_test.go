@@ -0,0 +1,98 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+func TestBcryptHasherMatch(t *testing.T) {
+	encoded, err := bcrypt.GenerateFromPassword([]byte("Password1"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt.GenerateFromPassword() error = %v", err)
+	}
+
+	if !BcryptHasher.Match("Password1", string(encoded)) {
+		t.Error("BcryptHasher.Match() = false for the correct password, want true")
+	}
+	if BcryptHasher.Match("password1", string(encoded)) {
+		t.Error("BcryptHasher.Match() = true for a case-mismatched password, want false")
+	}
+	if BcryptHasher.Match("wrong", string(encoded)) {
+		t.Error("BcryptHasher.Match() = true for the wrong password, want false")
+	}
+}
+
+func TestArgon2idHasherMatch(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	var time, memory uint32 = 3, 65536
+	var threads uint8 = 2
+
+	hash := argon2.IDKey([]byte("Summer2023"), salt, time, memory, threads, 32)
+	encoded := fmt.Sprintf("$argon2id$v=19$m=%d,t=%d,p=%d$%s$%s",
+		memory, time, threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash))
+
+	if !Argon2idHasher.Match("Summer2023", encoded) {
+		t.Error("Argon2idHasher.Match() = false for the correct password, want true")
+	}
+	if Argon2idHasher.Match("summer2023", encoded) {
+		t.Error("Argon2idHasher.Match() = true for a case-mismatched password, want false")
+	}
+	if Argon2idHasher.Match("wrong", encoded) {
+		t.Error("Argon2idHasher.Match() = true for the wrong password, want false")
+	}
+}
+
+func TestScryptHasherMatch(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	logN, r, p := uint64(15), uint64(8), uint64(1)
+
+	hash, err := scrypt.Key([]byte("hunter2pw"), salt, 1<<logN, int(r), int(p), 32)
+	if err != nil {
+		t.Fatalf("scrypt.Key() error = %v", err)
+	}
+	encoded := fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s",
+		logN, r, p,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash))
+
+	if !ScryptHasher.Match("hunter2pw", encoded) {
+		t.Error("ScryptHasher.Match() = false for the correct password, want true")
+	}
+	if ScryptHasher.Match("wrong", encoded) {
+		t.Error("ScryptHasher.Match() = true for the wrong password, want false")
+	}
+}
+
+func TestIsSaltedHash(t *testing.T) {
+	hashers := []PasswordHasher{BcryptHasher, Argon2idHasher, ScryptHasher}
+
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"$2a$10$abcdefghijklmnopqrstuv", true},
+		{"$argon2id$v=19$m=65536,t=3,p=2$salt$hash", true},
+		{"$scrypt$ln=15,r=8,p=1$salt$hash", true},
+		{"correcthorsebatterystaple", false},
+	}
+
+	for _, tt := range tests {
+		if got := isSaltedHash(tt.line, hashers); got != tt.want {
+			t.Errorf("isSaltedHash(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// confusables maps a handful of commonly-abused look-alike runes (Cyrillic,
+// Greek) onto their ASCII skeleton, derived from the Unicode confusables
+// list (https://www.unicode.org/Public/security/latest/confusables.txt).
+// Full-width/half-width forms and mathematical alphanumeric symbols are
+// folded separately, below.
+var confusables = map[rune]rune{
+	// Cyrillic lookalikes
+	'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y', 'х': 'x',
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O',
+	'Р': 'P', 'С': 'C', 'Т': 'T', 'Х': 'X',
+	// Greek lookalikes
+	'α': 'a', 'ο': 'o', 'ν': 'v', 'υ': 'u', 'ρ': 'p',
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K',
+	'Μ': 'M', 'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+}
+
+// doubleStruck maps the "blackboard bold" mathematical alphanumeric capitals
+// (e.g. 𝔸𝔹ℂ) onto their ASCII letter. Unlike the other mathematical styles,
+// this block has several gaps filled by pre-existing Unicode letter-like
+// symbols, so it's enumerated explicitly rather than computed from an offset.
+var doubleStruck = map[rune]rune{
+	0x1D538: 'A', 0x1D539: 'B', 0x2102: 'C', 0x1D53B: 'D', 0x1D53C: 'E',
+	0x1D53D: 'F', 0x1D53E: 'G', 0x210D: 'H', 0x1D540: 'I', 0x1D541: 'J',
+	0x1D542: 'K', 0x1D543: 'L', 0x1D544: 'M', 0x2115: 'N', 0x1D546: 'O',
+	0x2119: 'P', 0x211A: 'Q', 0x211D: 'R', 0x1D54A: 'S', 0x1D54B: 'T',
+	0x1D54C: 'U', 0x1D54D: 'V', 0x1D54E: 'W', 0x1D54F: 'X', 0x1D550: 'Y',
+	0x2124: 'Z',
+}
+
+// foldMathAlphanumeric folds a rune from the Mathematical Alphanumeric
+// Symbols block (U+1D400-U+1D7FF) onto its plain ASCII letter or digit, e.g.
+// the bold "𝐩𝐚𝐬𝐬𝐰𝐨𝐫𝐝" or italic "𝑝𝑎𝑠𝑠𝑤𝑜𝑟𝑑" onto "password". It covers the
+// bold, italic and bold italic styles, double-struck capitals and bold
+// digits, which are the styles most commonly abused to bypass naive
+// dictionary checks.
+func foldMathAlphanumeric(r rune) (rune, bool) {
+	switch {
+	case r >= 0x1D400 && r <= 0x1D419: // Bold Capital A-Z
+		return 'A' + (r - 0x1D400), true
+	case r >= 0x1D41A && r <= 0x1D433: // Bold Small a-z
+		return 'a' + (r - 0x1D41A), true
+	case r >= 0x1D434 && r <= 0x1D44D: // Italic Capital A-Z
+		return 'A' + (r - 0x1D434), true
+	case r >= 0x1D44E && r <= 0x1D454: // Italic Small a-g
+		return 'a' + (r - 0x1D44E), true
+	case r == 0x210E: // Italic Small h (PLANCK CONSTANT fills the gap)
+		return 'h', true
+	case r >= 0x1D456 && r <= 0x1D467: // Italic Small i-z
+		return 'a' + 8 + (r - 0x1D456), true
+	case r >= 0x1D468 && r <= 0x1D481: // Bold Italic Capital A-Z
+		return 'A' + (r - 0x1D468), true
+	case r >= 0x1D482 && r <= 0x1D49B: // Bold Italic Small a-z
+		return 'a' + (r - 0x1D482), true
+	case r >= 0x1D7CE && r <= 0x1D7D7: // Bold digits 0-9
+		return '0' + (r - 0x1D7CE), true
+	}
+	if mapped, ok := doubleStruck[r]; ok {
+		return mapped, true
+	}
+	return 0, false
+}
+
+// foldConfusables applies NFKC normalization, strips combining marks, folds
+// full-width/half-width forms to their normal-width counterpart, and maps
+// confusable runes onto their ASCII skeleton.
+func foldConfusables(s string) string {
+	s = norm.NFKC.String(s)
+	s = width.Fold.String(s)
+
+	if stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s); err == nil {
+		s = stripped
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case confusables[r] != 0:
+			b.WriteRune(confusables[r])
+		default:
+			if mapped, ok := foldMathAlphanumeric(r); ok {
+				b.WriteRune(mapped)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+
+	return strings.ToLower(b.String())
+}
+
+// normalize prepares a dictionary word or candidate password for lookup. With
+// foldUnicode set, it additionally runs the Unicode confusable-folding
+// pipeline (see Options.UnicodeFold) so that lookalike spellings of a
+// dictionary word are caught as well.
+func normalize(s string, foldUnicode bool) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if !foldUnicode {
+		return s
+	}
+	return foldConfusables(s)
+}
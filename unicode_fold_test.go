@@ -0,0 +1,49 @@
+/*
+ * crunchy - find common flaws in passwords
+ *     Copyright (c) 2017-2018, Christian Muehlhaeuser <muesli@gmail.com>
+ *
+ *   For license see LICENSE
+ */
+
+package crunchy
+
+import "testing"
+
+func TestNormalizeUnicodeFold(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"combining diaeresis", "pässwörd", "password"},
+		{"cyrillic lookalikes", "раssword", "password"}, // Cyrillic а, р
+		{"mathematical bold", "𝐩𝐚𝐬𝐬𝐰𝐨𝐫𝐝", "password"},
+		{"full-width", "ｐａｓｓｗｏｒｄ", "password"},
+		{"already ascii", "password", "password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalize(tt.in, true); got != tt.want {
+				t.Errorf("normalize(%q, true) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeWithoutFoldLeavesLookalikes(t *testing.T) {
+	in := "раssword" // Cyrillic а, р
+	if got := normalize(in, false); got == "password" {
+		t.Errorf("normalize(%q, false) unexpectedly folded to ASCII: %q", in, got)
+	}
+}
+
+func TestFoldMathAlphanumericDigits(t *testing.T) {
+	// Bold digit 0 (U+1D7CE) through bold digit 9 (U+1D7D7)
+	if r, ok := foldMathAlphanumeric(0x1D7CE); !ok || r != '0' {
+		t.Errorf("foldMathAlphanumeric(bold 0) = %q, %v, want '0', true", r, ok)
+	}
+	if r, ok := foldMathAlphanumeric(0x1D7D7); !ok || r != '9' {
+		t.Errorf("foldMathAlphanumeric(bold 9) = %q, %v, want '9', true", r, ok)
+	}
+}